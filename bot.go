@@ -1,9 +1,11 @@
 package telebot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"strconv"
 	"time"
 )
@@ -15,6 +17,43 @@ type Bot struct {
 	Messages  chan Message
 	Queries   chan Query
 	Callbacks chan Callback
+
+	// SecretToken, if set, is sent to Telegram when registering a
+	// webhook and is then expected back on every request delivered to
+	// the handler returned by ListenForWebhook, so the endpoint can
+	// reject requests that don't originate from Telegram.
+	SecretToken string
+
+	// MaxConnections caps the number of simultaneous HTTPS connections
+	// Telegram will use to deliver updates to a registered webhook.
+	// Zero leaves it to Telegram's default.
+	MaxConnections int
+
+	// RetryPolicy, if set, makes the Bot automatically retry calls that
+	// fail with a 429 or a chat migration instead of returning the
+	// APIError straight to the caller.
+	RetryPolicy *RetryPolicy
+
+	// Limiter, if set, throttles outbound calls to stay within
+	// Telegram's per-chat and global rate limits. Calls block until a
+	// slot opens up; see SendAsync for a non-blocking alternative.
+	Limiter *Limiter
+
+	// Errors receives errors encountered while polling (failed
+	// getUpdates calls and failed OffsetStore saves), instead of having
+	// them just logged.
+	// Sends are skipped if the channel is nil or the poll context is
+	// cancelled before a slot frees up.
+	Errors chan error
+
+	// OffsetStore, if set, persists the ID of the last update
+	// delivered so a restart resumes from where it left off instead of
+	// re-delivering or skipping updates. A nil OffsetStore keeps the
+	// offset in memory only, starting from zero on every Start/Listen.
+	OffsetStore OffsetStore
+
+	routes     []route
+	middleware []MiddlewareFunc
 }
 
 type messageResponse struct {
@@ -42,81 +81,145 @@ func NewBot(token string) (*Bot, error) {
 	}, nil
 }
 
-// Listen periodically looks for updates and delivers new messages
-// to the subscription channel.
-func (b *Bot) Listen(subscription chan Message, timeout time.Duration) {
-	go b.poll(subscription, nil, nil, timeout)
+const (
+	minPollBackoff = time.Second
+	maxPollBackoff = 30 * time.Second
+)
+
+// OffsetStore persists the ID of the last update a Bot has delivered,
+// so polling can resume from it across restarts instead of re-delivering
+// or skipping updates.
+type OffsetStore interface {
+	LoadOffset() (int, error)
+	SaveOffset(offset int) error
+}
+
+// Listen periodically looks for updates and delivers new messages to
+// the subscription channel, until ctx is cancelled.
+func (b *Bot) Listen(ctx context.Context, subscription chan Message, timeout time.Duration) {
+	go b.poll(ctx, subscription, nil, nil, timeout)
 }
 
-// Start periodically polls messages and/or updates to corresponding channels
-// from the bot object.
-func (b *Bot) Start(timeout time.Duration) {
-	b.poll(b.Messages, b.Queries, b.Callbacks, timeout)
+// Start periodically polls messages and/or updates to corresponding
+// channels from the bot object, until ctx is cancelled, in which case
+// it returns ctx.Err().
+func (b *Bot) Start(ctx context.Context, timeout time.Duration) error {
+	return b.poll(ctx, b.Messages, b.Queries, b.Callbacks, timeout)
 }
 
 func (b *Bot) poll(
+	ctx context.Context,
 	messages chan Message,
 	queries chan Query,
 	callbacks chan Callback,
 	timeout time.Duration,
-) {
+) error {
 	latestUpdate := 0
+	if b.OffsetStore != nil {
+		if offset, err := b.OffsetStore.LoadOffset(); err == nil {
+			latestUpdate = offset
+		}
+	}
+
+	backoff := minPollBackoff
 
 	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		updates, err := getUpdates(b.Token,
 			latestUpdate+1,
 			int(timeout/time.Second),
 		)
 
 		if err != nil {
-			log.Println("failed to get updates:", err)
+			b.reportError(ctx, err)
+
+			select {
+			case <-time.After(jitter(backoff)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			if backoff *= 2; backoff > maxPollBackoff {
+				backoff = maxPollBackoff
+			}
+
 			continue
 		}
 
+		backoff = minPollBackoff
+
 		for _, update := range updates {
-			if update.Payload != nil /* if message */ {
-				if messages == nil {
-					continue
+			if update.Payload != nil && messages != nil /* if message */ {
+				select {
+				case messages <- *update.Payload:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
-
-				messages <- *update.Payload
-			} else if update.Query != nil /* if query */ {
-				if queries == nil {
-					continue
+			} else if update.Query != nil && queries != nil /* if query */ {
+				select {
+				case queries <- *update.Query:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
-
-				queries <- *update.Query
-			} else if update.Callback != nil {
-				if callbacks == nil {
-					continue
+			} else if update.Callback != nil && callbacks != nil {
+				select {
+				case callbacks <- *update.Callback:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
+			}
 
-				callbacks <- *update.Callback
+			if len(b.routes) > 0 {
+				b.route(update)
 			}
 
 			latestUpdate = update.ID
+
+			if b.OffsetStore != nil {
+				if err := b.OffsetStore.SaveOffset(latestUpdate); err != nil {
+					b.reportError(ctx, err)
+				}
+			}
 		}
 	}
+}
+
+// reportError delivers err on Bot.Errors, falling back to logging it if
+// Errors is nil, and giving up without blocking forever if ctx is
+// cancelled before a slot frees up.
+func (b *Bot) reportError(ctx context.Context, err error) {
+	if b.Errors == nil {
+		log.Println("telebot: polling error:", err)
+		return
+	}
+
+	select {
+	case b.Errors <- err:
+	case <-ctx.Done():
+	}
+}
 
+// jitter returns d plus a random amount of up to half of d, so repeated
+// failures don't all retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
 }
 
 func (b *Bot) sendRawMessage(command string, params map[string]string) (Message, error) {
 	var responseRecieved messageResponseReceived
 
-	responseJSON, err := sendCommand(command, b.Token, params)
+	responseJSON, err := b.call(command, params)
 	if err != nil {
 		return responseRecieved.Result, err
 	}
 
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
+	if err := json.Unmarshal(responseJSON, &responseRecieved); err != nil {
 		return responseRecieved.Result, err
 	}
 
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
 	return responseRecieved.Result, nil
 }
 
@@ -171,23 +274,8 @@ func (b *Bot) EditInlineMessageText(messageID string, text string, options *Send
 		embedSendOptions(params, options)
 	}
 
-	var responseRecieved messageResponse
-
-	responseJSON, err := sendCommand("editMessageText", b.Token, params)
-	if err != nil {
-		return err
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return err
-	}
-
-	if !responseRecieved.Ok {
-		return fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	return nil
+	_, err := b.call("editMessageText", params)
+	return err
 }
 
 // SendPhoto sends a photo object to recipient.
@@ -202,41 +290,17 @@ func (b *Bot) SendPhoto(recipient Recipient, photo *Photo, options *SendOptions)
 		"caption": photo.Caption,
 	}
 
-	if options != nil {
-		embedSendOptions(params, options)
-	}
-
-	var responseJSON []byte
-	var err error
-	var responseRecieved messageResponseReceived
-
-	if photo.Exists() {
-		params["photo"] = photo.FileID
-		responseJSON, err = sendCommand("sendPhoto", b.Token, params)
-	} else {
-		responseJSON, err = sendFile("sendPhoto", b.Token, "photo",
-			photo.filename, params)
-	}
-
+	result, err := b.sendMedia("sendPhoto", "photo", photo.File, params, options)
 	if err != nil {
-		return responseRecieved.Result, err
+		return result, err
 	}
 
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	thumbnails := &responseRecieved.Result.Photo
+	thumbnails := result.Photo
 	filename := photo.filename
-	photo.File = (*thumbnails)[len(*thumbnails)-1].File
+	photo.File = thumbnails[len(thumbnails)-1].File
 	photo.filename = filename
 
-	return responseRecieved.Result, nil
+	return result, nil
 }
 
 // SendAudio sends an audio object to recipient.
@@ -250,40 +314,16 @@ func (b *Bot) SendAudio(recipient Recipient, audio *Audio, options *SendOptions)
 		"chat_id": recipient.Destination(),
 	}
 
-	if options != nil {
-		embedSendOptions(params, options)
-	}
-
-	var responseJSON []byte
-	var err error
-	var responseRecieved messageResponseReceived
-
-	if audio.Exists() {
-		params["audio"] = audio.FileID
-		responseJSON, err = sendCommand("sendAudio", b.Token, params)
-	} else {
-		responseJSON, err = sendFile("sendAudio", b.Token, "audio",
-			audio.filename, params)
-	}
-
+	result, err := b.sendMedia("sendAudio", "audio", audio.File, params, options)
 	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
+		return result, err
 	}
 
 	filename := audio.filename
-	*audio = responseRecieved.Result.Audio
+	*audio = result.Audio
 	audio.filename = filename
 
-	return responseRecieved.Result, nil
+	return result, nil
 }
 
 // SendDocument sends a general document object to recipient.
@@ -297,40 +337,16 @@ func (b *Bot) SendDocument(recipient Recipient, doc *Document, options *SendOpti
 		"chat_id": recipient.Destination(),
 	}
 
-	if options != nil {
-		embedSendOptions(params, options)
-	}
-
-	var responseJSON []byte
-	var err error
-	var responseRecieved messageResponseReceived
-
-	if doc.Exists() {
-		params["document"] = doc.FileID
-		responseJSON, err = sendCommand("sendDocument", b.Token, params)
-	} else {
-		responseJSON, err = sendFile("sendDocument", b.Token, "document",
-			doc.filename, params)
-	}
-
+	result, err := b.sendMedia("sendDocument", "document", doc.File, params, options)
 	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
+		return result, err
 	}
 
 	filename := doc.filename
-	*doc = responseRecieved.Result.Document
+	*doc = result.Document
 	doc.filename = filename
 
-	return responseRecieved.Result, nil
+	return result, nil
 }
 
 // SendSticker sends a general document object to recipient.
@@ -344,40 +360,16 @@ func (b *Bot) SendSticker(recipient Recipient, sticker *Sticker, options *SendOp
 		"chat_id": recipient.Destination(),
 	}
 
-	if options != nil {
-		embedSendOptions(params, options)
-	}
-
-	var responseJSON []byte
-	var err error
-	var responseRecieved messageResponseReceived
-
-	if sticker.Exists() {
-		params["sticker"] = sticker.FileID
-		responseJSON, err = sendCommand("sendSticker", b.Token, params)
-	} else {
-		responseJSON, err = sendFile("sendSticker", b.Token, "sticker",
-			sticker.filename, params)
-	}
-
-	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
+	result, err := b.sendMedia("sendSticker", "sticker", sticker.File, params, options)
 	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
+		return result, err
 	}
 
 	filename := sticker.filename
-	*sticker = responseRecieved.Result.Sticker
+	*sticker = result.Sticker
 	sticker.filename = filename
 
-	return responseRecieved.Result, nil
+	return result, nil
 }
 
 // SendVideo sends a general document object to recipient.
@@ -391,40 +383,16 @@ func (b *Bot) SendVideo(recipient Recipient, video *Video, options *SendOptions)
 		"chat_id": recipient.Destination(),
 	}
 
-	if options != nil {
-		embedSendOptions(params, options)
-	}
-
-	var responseJSON []byte
-	var err error
-	var responseRecieved messageResponseReceived
-
-	if video.Exists() {
-		params["video"] = video.FileID
-		responseJSON, err = sendCommand("sendVideo", b.Token, params)
-	} else {
-		responseJSON, err = sendFile("sendVideo", b.Token, "video",
-			video.filename, params)
-	}
-
+	result, err := b.sendMedia("sendVideo", "video", video.File, params, options)
 	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return responseRecieved.Result, err
-	}
-
-	if !responseRecieved.Ok {
-		return responseRecieved.Result, fmt.Errorf("telebot: %s", responseRecieved.Description)
+		return result, err
 	}
 
 	filename := video.filename
-	*video = responseRecieved.Result.Video
+	*video = result.Video
 	video.filename = filename
 
-	return responseRecieved.Result, nil
+	return result, nil
 }
 
 // SendLocation sends a general document object to recipient.
@@ -481,26 +449,8 @@ func (b *Bot) SendChatAction(recipient Recipient, action string) error {
 		"action":  action,
 	}
 
-	responseJSON, err := sendCommand("sendChatAction", b.Token, params)
-	if err != nil {
-		return err
-	}
-
-	var responseRecieved struct {
-		Ok          bool
-		Description string
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return err
-	}
-
-	if !responseRecieved.Ok {
-		return fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	return nil
+	_, err := b.call("sendChatAction", params)
+	return err
 }
 
 // Respond publishes a set of responses for an inline query.
@@ -510,32 +460,14 @@ func (b *Bot) Respond(query Query, results []Result) error {
 		"inline_query_id": query.ID,
 	}
 
-	if res, err := json.Marshal(results); err == nil {
-		params["results"] = string(res)
-	} else {
-		return err
-	}
-
-	responseJSON, err := sendCommand("answerInlineQuery", b.Token, params)
+	res, err := json.Marshal(results)
 	if err != nil {
 		return err
 	}
+	params["results"] = string(res)
 
-	var responseRecieved struct {
-		Ok          bool
-		Description string
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return err
-	}
-
-	if !responseRecieved.Ok {
-		return fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	return nil
+	_, err = b.call("answerInlineQuery", params)
+	return err
 }
 
 // AnswerInlineQuery sends a response for a given inline query. A query can
@@ -544,26 +476,8 @@ func (b *Bot) Respond(query Query, results []Result) error {
 func (b *Bot) AnswerInlineQuery(query *Query, response *QueryResponse) error {
 	response.QueryID = query.ID
 
-	responseJSON, err := sendCommand("answerInlineQuery", b.Token, response)
-	if err != nil {
-		return err
-	}
-
-	var responseRecieved struct {
-		Ok          bool
-		Description string
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return err
-	}
-
-	if !responseRecieved.Ok {
-		return fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	return nil
+	_, err := b.call("answerInlineQuery", response)
+	return err
 }
 
 // AnswerCallbackQuery sends a response for a given callback query. A callback can
@@ -572,24 +486,6 @@ func (b *Bot) AnswerInlineQuery(query *Query, response *QueryResponse) error {
 func (b *Bot) AnswerCallbackQuery(callback *Callback, response *CallbackResponse) error {
 	response.CallbackID = callback.ID
 
-	responseJSON, err := sendCommand("answerCallbackQuery", b.Token, response)
-	if err != nil {
-		return err
-	}
-
-	var responseRecieved struct {
-		Ok          bool
-		Description string
-	}
-
-	err = json.Unmarshal(responseJSON, &responseRecieved)
-	if err != nil {
-		return err
-	}
-
-	if !responseRecieved.Ok {
-		return fmt.Errorf("telebot: %s", responseRecieved.Description)
-	}
-
-	return nil
+	_, err := b.call("answerCallbackQuery", response)
+	return err
 }
@@ -0,0 +1,20 @@
+package telebot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	d := time.Second
+
+	for i := 0; i < 100; i++ {
+		got := jitter(d)
+		if got < d {
+			t.Fatalf("jitter(%v) = %v, want >= %v", d, got, d)
+		}
+		if max := d + d/2; got > max {
+			t.Fatalf("jitter(%v) = %v, want <= %v", d, got, max)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package telebot
+
+import "errors"
+
+// ErrNoMessage is returned by Context methods that need an incoming
+// Message — Reply and Edit — when the Context instead wraps a Query or
+// a Callback. The Message a Callback carries is not used for this.
+var ErrNoMessage = errors.New("telebot: context has no message")
+
+// ErrNoCallback is returned by Respond when the Context doesn't wrap an
+// incoming Callback of its own, e.g. one built for an OnText handler.
+var ErrNoCallback = errors.New("telebot: context has no callback")
+
+// ErrNoQuery is returned by Answer when the Context doesn't wrap an
+// incoming Query of its own, e.g. one built for an OnText handler.
+var ErrNoQuery = errors.New("telebot: context has no query")
+
+// Context wraps a single incoming Message, Query or Callback and exposes
+// convenience methods for responding to it, so handlers don't need to
+// thread a *Bot and the raw update through by hand.
+type Context interface {
+	// Bot returns the Bot instance that produced this Context.
+	Bot() *Bot
+
+	// Message returns the incoming message, or nil if this Context
+	// wraps a Query or Callback instead.
+	Message() *Message
+
+	// Query returns the incoming inline query, or nil if this Context
+	// wraps a Message or Callback instead.
+	Query() *Query
+
+	// Callback returns the incoming callback, or nil if this Context
+	// wraps a Message or Query instead.
+	Callback() *Callback
+
+	// Reply sends a text message back to the chat the incoming
+	// Message originated from.
+	Reply(text string, options *SendOptions) (Message, error)
+
+	// Send sends a text message to an arbitrary recipient, just like
+	// Bot.SendMessage.
+	Send(to Recipient, text string, options *SendOptions) (Message, error)
+
+	// Edit edits the text of the incoming Message.
+	Edit(text string, options *SendOptions) (Message, error)
+
+	// Respond answers the incoming Callback.
+	Respond(response *CallbackResponse) error
+
+	// Answer answers the incoming inline Query.
+	Answer(response *QueryResponse) error
+}
+
+type nativeContext struct {
+	bot      *Bot
+	message  *Message
+	query    *Query
+	callback *Callback
+}
+
+func (c *nativeContext) Bot() *Bot {
+	return c.bot
+}
+
+func (c *nativeContext) Message() *Message {
+	return c.message
+}
+
+func (c *nativeContext) Query() *Query {
+	return c.query
+}
+
+func (c *nativeContext) Callback() *Callback {
+	return c.callback
+}
+
+func (c *nativeContext) Reply(text string, options *SendOptions) (Message, error) {
+	if c.message == nil {
+		return Message{}, ErrNoMessage
+	}
+	return c.bot.SendMessage(c.message.Chat, text, options)
+}
+
+func (c *nativeContext) Send(to Recipient, text string, options *SendOptions) (Message, error) {
+	return c.bot.SendMessage(to, text, options)
+}
+
+func (c *nativeContext) Edit(text string, options *SendOptions) (Message, error) {
+	if c.message == nil {
+		return Message{}, ErrNoMessage
+	}
+	return c.bot.EditMessageText(*c.message, text, options)
+}
+
+func (c *nativeContext) Respond(response *CallbackResponse) error {
+	if c.callback == nil {
+		return ErrNoCallback
+	}
+	return c.bot.AnswerCallbackQuery(c.callback, response)
+}
+
+func (c *nativeContext) Answer(response *QueryResponse) error {
+	if c.query == nil {
+		return ErrNoQuery
+	}
+	return c.bot.AnswerInlineQuery(c.query, response)
+}
@@ -0,0 +1,97 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// responseParameters mirrors Telegram's "parameters" field returned
+// alongside certain failed API calls, carrying machine-readable detail
+// about why the call failed and how to recover from it.
+type responseParameters struct {
+	RetryAfter      int   `json:"retry_after"`
+	MigrateToChatID int64 `json:"migrate_to_chat_id"`
+}
+
+// apiResponse is the envelope every Telegram Bot API call replies with.
+// Result is left as raw JSON so callers can unmarshal it into whatever
+// type the endpoint actually returns.
+type apiResponse struct {
+	Ok          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *responseParameters `json:"parameters"`
+	Result      json.RawMessage     `json:"result"`
+}
+
+// APIError is returned whenever Telegram reports a call as failed. It
+// carries the raw error_code/description pair along with whatever
+// structured recovery information Telegram supplied, so callers that
+// need the specifics don't have to parse Description themselves.
+type APIError struct {
+	Code            int
+	Description     string
+	RetryAfter      int
+	MigrateToChatID int64
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telebot: %s (%d)", e.Description, e.Code)
+}
+
+// Is reports whether target is an APIError describing the same failure,
+// so sentinel errors such as ErrTooManyRequests can be matched with
+// errors.Is instead of comparing Description strings by hand. Matching
+// is by Code plus a substring check on Description rather than full
+// equality, since Telegram appends dynamic detail to some descriptions
+// (e.g. "Too Many Requests: retry after 5") that would otherwise never
+// equal the sentinel's fixed wording.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code && strings.Contains(e.Description, t.Description)
+}
+
+// Sentinel errors for the API failures callers most commonly need to
+// branch on. Match them with errors.Is(err, telebot.ErrTooManyRequests).
+// Descriptions are kept to the distinguishing core of Telegram's
+// wording (no "Bad Request: "/"Forbidden: " prefix) so Is can match
+// them as a substring of whatever Telegram actually sent.
+var (
+	ErrTooManyRequests    = &APIError{Code: 429, Description: "Too Many Requests"}
+	ErrChatNotFound       = &APIError{Code: 400, Description: "chat not found"}
+	ErrBlockedByUser      = &APIError{Code: 403, Description: "bot was blocked by the user"}
+	ErrMessageNotModified = &APIError{Code: 400, Description: "message is not modified"}
+	ErrGroupMigrated      = &APIError{Code: 400, Description: "group chat was upgraded to a supergroup chat"}
+)
+
+// newAPIError builds an APIError from a failed apiResponse, keeping
+// Telegram's own Description intact so callers see the full detail
+// while errors.Is still matches sentinels by Code and substring.
+func newAPIError(resp apiResponse) *APIError {
+	err := &APIError{
+		Code:        resp.ErrorCode,
+		Description: resp.Description,
+	}
+
+	if resp.Parameters != nil {
+		err.RetryAfter = resp.Parameters.RetryAfter
+		err.MigrateToChatID = resp.Parameters.MigrateToChatID
+	}
+
+	return err
+}
+
+// RetryPolicy configures automatic retries performed by Bot for API
+// calls that fail in a recoverable way: a 429 with a retry_after, or a
+// chat migration with a migrate_to_chat_id. A nil RetryPolicy (the
+// default) disables retries and failures are returned to the caller
+// as-is.
+type RetryPolicy struct {
+	// MaxRetries caps how many times a single call will be retried
+	// before giving up and returning the APIError to the caller.
+	MaxRetries int
+}
@@ -0,0 +1,54 @@
+package telebot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{
+			name:   "matches realistic dynamic description",
+			err:    &APIError{Code: 429, Description: "Too Many Requests: retry after 5"},
+			target: ErrTooManyRequests,
+			want:   true,
+		},
+		{
+			name:   "matches exact description",
+			err:    &APIError{Code: 400, Description: "chat not found"},
+			target: ErrChatNotFound,
+			want:   true,
+		},
+		{
+			name:   "code mismatch",
+			err:    &APIError{Code: 400, Description: "Too Many Requests"},
+			target: ErrTooManyRequests,
+			want:   false,
+		},
+		{
+			name:   "description doesn't contain sentinel text",
+			err:    &APIError{Code: 400, Description: "message to edit not found"},
+			target: ErrMessageNotModified,
+			want:   false,
+		},
+		{
+			name:   "target isn't an APIError",
+			err:    &APIError{Code: 400, Description: "chat not found"},
+			target: errors.New("chat not found"),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%+v, %+v) = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package telebot
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter throttles outbound API calls to stay within Telegram's
+// documented quotas: a global rate across all chats, a per-chat rate
+// for private/individual chats, and a looser per-minute rate for group
+// chats (recognized by their negative chat_id). A nil Limiter on Bot
+// disables throttling entirely.
+type Limiter struct {
+	// GlobalPerSecond caps messages/sec across every chat. Zero uses
+	// Telegram's documented default of 30.
+	GlobalPerSecond int
+
+	// ChatPerSecond caps messages/sec to any single private chat. Zero
+	// uses Telegram's documented default of 1.
+	ChatPerSecond int
+
+	// GroupPerMinute caps messages/min to any single group chat. Zero
+	// uses Telegram's documented default of 20.
+	GroupPerMinute int
+
+	mu     sync.Mutex
+	global *tokenBucket
+	chats  map[string]*tokenBucket
+}
+
+// wait blocks until a slot is available both for chatID and globally,
+// in that order, so a busy chat never starves the global budget.
+func (l *Limiter) wait(chatID string) {
+	if l == nil {
+		return
+	}
+
+	l.chatBucket(chatID).wait()
+	l.globalBucket().wait()
+}
+
+func (l *Limiter) globalBucket() *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.global == nil {
+		rate := l.GlobalPerSecond
+		if rate == 0 {
+			rate = 30
+		}
+		l.global = newTokenBucket(rate, time.Second)
+	}
+
+	return l.global
+}
+
+func (l *Limiter) chatBucket(chatID string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.chats == nil {
+		l.chats = make(map[string]*tokenBucket)
+	}
+
+	bucket, ok := l.chats[chatID]
+	if !ok {
+		// Telegram assigns negative chat_ids to groups and
+		// supergroups, which is the only way to tell them apart from
+		// the recipient's Destination() alone.
+		if strings.HasPrefix(chatID, "-") {
+			rate := l.GroupPerMinute
+			if rate == 0 {
+				rate = 20
+			}
+			bucket = newTokenBucket(rate, time.Minute)
+		} else {
+			rate := l.ChatPerSecond
+			if rate == 0 {
+				rate = 1
+			}
+			bucket = newTokenBucket(rate, time.Second)
+		}
+		l.chats[chatID] = bucket
+	}
+
+	return bucket
+}
+
+// tokenBucket enforces a minimum interval between successive calls to
+// wait, blocking the caller until that interval has elapsed since the
+// last one. Concurrent callers queue on the mutex, which keeps sends to
+// the same chat in the order they arrived.
+type tokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(ratePerInterval int, interval time.Duration) *tokenBucket {
+	return &tokenBucket{interval: interval / time.Duration(ratePerInterval)}
+}
+
+func (t *tokenBucket) wait() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	next := t.last.Add(t.interval)
+
+	if next.After(now) {
+		time.Sleep(next.Sub(now))
+		now = next
+	}
+
+	t.last = now
+}
+
+// SendResult is delivered on the channel returned by SendAsync once the
+// send completes, or fails.
+type SendResult struct {
+	Message Message
+	Err     error
+}
+
+// SendAsync sends a text message like SendMessage, but returns
+// immediately with a channel that receives the result once it's ready,
+// instead of blocking the caller on Bot.Limiter.
+func (b *Bot) SendAsync(recipient Recipient, message string, options *SendOptions) <-chan SendResult {
+	result := make(chan SendResult, 1)
+
+	go func() {
+		msg, err := b.SendMessage(recipient, message, options)
+		result <- SendResult{Message: msg, Err: err}
+	}()
+
+	return result
+}
@@ -0,0 +1,79 @@
+package telebot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketInterval(t *testing.T) {
+	tests := []struct {
+		name            string
+		ratePerInterval int
+		interval        time.Duration
+		want            time.Duration
+	}{
+		{"30 per second", 30, time.Second, time.Second / 30},
+		{"1 per second", 1, time.Second, time.Second},
+		{"20 per minute", 20, time.Minute, time.Minute / 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(tt.ratePerInterval, tt.interval)
+			if b.interval != tt.want {
+				t.Errorf("interval = %v, want %v", b.interval, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketWaitSpacing(t *testing.T) {
+	b := newTokenBucket(100, time.Second) // 10ms apart
+
+	start := time.Now()
+	b.wait()
+	b.wait()
+	b.wait()
+	elapsed := time.Since(start)
+
+	if min := 2 * b.interval; elapsed < min {
+		t.Errorf("three waits took %v, want at least %v", elapsed, min)
+	}
+}
+
+func TestLimiterChatBucketByChatKind(t *testing.T) {
+	l := &Limiter{}
+
+	group := l.chatBucket("-100123")
+	if want := time.Minute / 20; group.interval != want {
+		t.Errorf("group chat interval = %v, want %v", group.interval, want)
+	}
+
+	private := l.chatBucket("123")
+	if want := time.Second; private.interval != want {
+		t.Errorf("private chat interval = %v, want %v", private.interval, want)
+	}
+}
+
+func TestLimiterChatBucketRespectsOverrides(t *testing.T) {
+	l := &Limiter{ChatPerSecond: 2, GroupPerMinute: 40}
+
+	group := l.chatBucket("-1")
+	if want := time.Minute / 40; group.interval != want {
+		t.Errorf("group chat interval = %v, want %v", group.interval, want)
+	}
+
+	private := l.chatBucket("1")
+	if want := time.Second / 2; private.interval != want {
+		t.Errorf("private chat interval = %v, want %v", private.interval, want)
+	}
+}
+
+func TestLimiterNilIsNoop(t *testing.T) {
+	var l *Limiter
+	start := time.Now()
+	l.wait("123")
+	if time.Since(start) > 10*time.Millisecond {
+		t.Error("nil Limiter.wait blocked instead of returning immediately")
+	}
+}
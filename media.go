@@ -0,0 +1,277 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// sendMediaRaw uploads or references a single media file and returns
+// the raw API response JSON, without assuming anything about the shape
+// of the Result it carries. It's the one place that decides whether a
+// file needs uploading (file.Exists() is false) or can simply be
+// referenced by its FileID / URL.
+func (b *Bot) sendMediaRaw(command, field string, file File, params map[string]string, options *SendOptions) ([]byte, error) {
+	if options != nil {
+		embedSendOptions(params, options)
+	}
+
+	if file.Exists() {
+		params[field] = file.FileID
+		return b.call(command, params)
+	}
+
+	return b.callFile(command, field, file.filename, params)
+}
+
+// sendMedia is sendMediaRaw plus the usual Message unmarshaling shared
+// by SendPhoto, SendAudio, SendDocument, SendSticker and SendVideo.
+func (b *Bot) sendMedia(command, field string, file File, params map[string]string, options *SendOptions) (Message, error) {
+	var responseRecieved messageResponseReceived
+
+	responseJSON, err := b.sendMediaRaw(command, field, file, params, options)
+	if err != nil {
+		return responseRecieved.Result, err
+	}
+
+	if err := json.Unmarshal(responseJSON, &responseRecieved); err != nil {
+		return responseRecieved.Result, err
+	}
+
+	return responseRecieved.Result, nil
+}
+
+// FileFromDisk builds a File that will be uploaded from a local path.
+func FileFromDisk(path string) File {
+	return File{filename: path}
+}
+
+// FileFromURL builds a File that references remote content by URL,
+// which Telegram will fetch itself rather than have it uploaded.
+func FileFromURL(url string) File {
+	return File{FileID: url}
+}
+
+// FileFromID builds a File aliasing content Telegram already has,
+// identified by a previously-seen FileID.
+func FileFromID(fileID string) File {
+	return File{FileID: fileID}
+}
+
+// FileFromReader spools r to a temporary file on disk and builds a File
+// from it, since the underlying multipart upload reads from a path
+// rather than an arbitrary io.Reader.
+func FileFromReader(r io.Reader) (File, error) {
+	tmp, err := os.CreateTemp("", "telebot-upload-*")
+	if err != nil {
+		return File{}, err
+	}
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return File{}, err
+	}
+
+	return File{filename: tmp.Name()}, nil
+}
+
+// decodeMediaResult unmarshals a media send response's Result into the
+// returned Message and, if extra is non-nil, also unmarshals the
+// Result's jsonField (e.g. "video_note") into extra. It's the shared
+// unmarshal step behind SendVideoNote, SendVoice and SendAnimation,
+// which each want a different extra field back alongside the Message.
+func decodeMediaResult(responseJSON []byte, jsonField string, extra interface{}) (Message, error) {
+	var responseRecieved struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(responseJSON, &responseRecieved); err != nil {
+		return Message{}, err
+	}
+
+	var msg Message
+	if err := json.Unmarshal(responseRecieved.Result, &msg); err != nil {
+		return Message{}, err
+	}
+
+	if extra != nil {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(responseRecieved.Result, &fields); err != nil {
+			return Message{}, err
+		}
+		if raw, ok := fields[jsonField]; ok {
+			if err := json.Unmarshal(raw, extra); err != nil {
+				return Message{}, err
+			}
+		}
+	}
+
+	return msg, nil
+}
+
+// VideoNote represents a round "video message" as introduced in
+// Telegram Bot API 4.0.
+type VideoNote struct {
+	File
+	Duration int
+}
+
+// Voice represents a voice note.
+type Voice struct {
+	File
+	Caption  string
+	Duration int
+}
+
+// Animation represents an animation file (GIF or soundless H.264/MPEG-4
+// AVC video).
+type Animation struct {
+	File
+	Caption  string
+	Duration int
+}
+
+// SendVideoNote sends a round video message to recipient.
+//
+// On success, the video note object is aliased to its copy on the
+// Telegram servers, just like SendVideo does for Video.
+func (b *Bot) SendVideoNote(recipient Recipient, note *VideoNote, options *SendOptions) (Message, error) {
+	params := map[string]string{
+		"chat_id": recipient.Destination(),
+	}
+
+	responseJSON, err := b.sendMediaRaw("sendVideoNote", "video_note", note.File, params, options)
+	if err != nil {
+		return Message{}, err
+	}
+
+	filename := note.filename
+	msg, err := decodeMediaResult(responseJSON, "video_note", note)
+	if err != nil {
+		return Message{}, err
+	}
+	note.filename = filename
+
+	return msg, nil
+}
+
+// SendVoice sends a voice note to recipient.
+//
+// On success, the voice object is aliased to its copy on the Telegram
+// servers, just like SendAudio does for Audio.
+func (b *Bot) SendVoice(recipient Recipient, voice *Voice, options *SendOptions) (Message, error) {
+	params := map[string]string{
+		"chat_id": recipient.Destination(),
+		"caption": voice.Caption,
+	}
+
+	responseJSON, err := b.sendMediaRaw("sendVoice", "voice", voice.File, params, options)
+	if err != nil {
+		return Message{}, err
+	}
+
+	filename := voice.filename
+	msg, err := decodeMediaResult(responseJSON, "voice", voice)
+	if err != nil {
+		return Message{}, err
+	}
+	voice.filename = filename
+
+	return msg, nil
+}
+
+// SendAnimation sends an animation (GIF or soundless video) to
+// recipient.
+//
+// On success, the animation object is aliased to its copy on the
+// Telegram servers, just like SendVideo does for Video.
+func (b *Bot) SendAnimation(recipient Recipient, animation *Animation, options *SendOptions) (Message, error) {
+	params := map[string]string{
+		"chat_id": recipient.Destination(),
+		"caption": animation.Caption,
+	}
+
+	responseJSON, err := b.sendMediaRaw("sendAnimation", "animation", animation.File, params, options)
+	if err != nil {
+		return Message{}, err
+	}
+
+	filename := animation.filename
+	msg, err := decodeMediaResult(responseJSON, "animation", animation)
+	if err != nil {
+		return Message{}, err
+	}
+	animation.filename = filename
+
+	return msg, nil
+}
+
+// InputMedia describes one item of an album sent with SendAlbum: its
+// Telegram media type ("photo" or "video") and the file to send, which
+// may already exist on Telegram's servers or still need uploading.
+type InputMedia struct {
+	Kind    string
+	Media   File
+	Caption string
+}
+
+// SendAlbum posts a photo/video album in a single message. Items whose
+// File already exists on Telegram's servers are referenced by FileID or
+// URL directly; items that still need uploading are attached as
+// "attach://<name>" parts of one multipart sendMediaGroup request, so
+// nothing goes out as a standalone message ahead of the album.
+func (b *Bot) SendAlbum(recipient Recipient, media []InputMedia, options *SendOptions) ([]Message, error) {
+	items := make([]map[string]string, len(media))
+	files := make(map[string]string)
+
+	for i, m := range media {
+		mediaRef := m.Media.FileID
+
+		if !m.Media.Exists() {
+			mediaRef = fmt.Sprintf("attach://file%d", i)
+			files[fmt.Sprintf("file%d", i)] = m.Media.filename
+		}
+
+		item := map[string]string{
+			"type":  m.Kind,
+			"media": mediaRef,
+		}
+		if m.Caption != "" {
+			item["caption"] = m.Caption
+		}
+		items[i] = item
+	}
+
+	mediaJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"chat_id": recipient.Destination(),
+		"media":   string(mediaJSON),
+	}
+
+	if options != nil {
+		embedSendOptions(params, options)
+	}
+
+	var responseJSON []byte
+	if len(files) > 0 {
+		responseJSON, err = b.callMultipart("sendMediaGroup", params, files)
+	} else {
+		responseJSON, err = b.call("sendMediaGroup", params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var responseRecieved struct {
+		Result []Message `json:"result"`
+	}
+	if err := json.Unmarshal(responseJSON, &responseRecieved); err != nil {
+		return nil, err
+	}
+
+	return responseRecieved.Result, nil
+}
@@ -0,0 +1,68 @@
+package telebot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// sendMultipart posts a single multipart/form-data request carrying
+// params as plain fields and files as additional parts, keyed by the
+// field name callers reference from params via "attach://<name>". It's
+// the building block SendAlbum uses to ship every item of an album,
+// uploaded or not, in one request instead of one per file.
+func sendMultipart(token, command string, params map[string]string, files map[string]string) ([]byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key, value := range params {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, path := range files {
+		if err := attachFile(writer, name, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/%s", token, command)
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func attachFile(writer *multipart.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	part, err := writer.CreateFormFile(name, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, f)
+	return err
+}
@@ -0,0 +1,81 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// call invokes a plain (non-file) API command, applying Bot.RetryPolicy
+// to the result before handing back the raw response JSON. params is
+// either a map[string]string or a struct tagged for JSON encoding, just
+// like sendCommand itself accepts.
+func (b *Bot) call(command string, params interface{}) ([]byte, error) {
+	return b.retry(params, func() ([]byte, error) {
+		return sendCommand(command, b.Token, params)
+	})
+}
+
+// callFile invokes an API command whose payload is uploaded as
+// multipart content, applying Bot.RetryPolicy the same way call does.
+func (b *Bot) callFile(command, fieldName, path string, params map[string]string) ([]byte, error) {
+	return b.retry(params, func() ([]byte, error) {
+		return sendFile(command, b.Token, fieldName, path, params)
+	})
+}
+
+// callMultipart invokes an API command whose payload carries several
+// file attachments in one multipart request, referenced from params by
+// "attach://<name>", applying Bot.RetryPolicy the same way call does.
+func (b *Bot) callMultipart(command string, params map[string]string, files map[string]string) ([]byte, error) {
+	return b.retry(params, func() ([]byte, error) {
+		return sendMultipart(b.Token, command, params, files)
+	})
+}
+
+// retry runs do, parsing its result as an apiResponse. On failure, if
+// RetryPolicy allows it, it sleeps out a 429's retry_after or rewrites
+// a map[string]string params' chat_id to follow a group migration, then
+// tries again.
+func (b *Bot) retry(params interface{}, do func() ([]byte, error)) ([]byte, error) {
+	attempts := 0
+
+	for {
+		if mapParams, ok := params.(map[string]string); ok {
+			b.Limiter.wait(mapParams["chat_id"])
+		}
+
+		responseJSON, err := do()
+		if err != nil {
+			return nil, err
+		}
+
+		var resp apiResponse
+		if err := json.Unmarshal(responseJSON, &resp); err != nil {
+			return nil, err
+		}
+
+		if resp.Ok {
+			return responseJSON, nil
+		}
+
+		apiErr := newAPIError(resp)
+
+		if b.RetryPolicy != nil && attempts < b.RetryPolicy.MaxRetries {
+			if errors.Is(apiErr, ErrTooManyRequests) && apiErr.RetryAfter > 0 {
+				attempts++
+				time.Sleep(time.Duration(apiErr.RetryAfter) * time.Second)
+				continue
+			}
+
+			if mapParams, ok := params.(map[string]string); ok && apiErr.MigrateToChatID != 0 {
+				attempts++
+				mapParams["chat_id"] = strconv.FormatInt(apiErr.MigrateToChatID, 10)
+				continue
+			}
+		}
+
+		return responseJSON, apiErr
+	}
+}
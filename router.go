@@ -0,0 +1,123 @@
+package telebot
+
+import (
+	"log"
+	"regexp"
+	"strings"
+)
+
+// Sentinel endpoints matched against the kind of update received rather
+// than its text, for use with Handle. They're prefixed with a control
+// character so they can never collide with a literal command such as
+// "/start".
+const (
+	OnText     = "\atext"
+	OnPhoto    = "\aphoto"
+	OnDocument = "\adocument"
+	OnQuery    = "\aquery"
+	OnCallback = "\acallback"
+)
+
+// HandlerFunc is invoked with a Context wrapping the matched update.
+type HandlerFunc func(ctx Context) error
+
+// MiddlewareFunc wraps a HandlerFunc with cross-cutting behavior such as
+// logging, recovery or rate limiting. Middlewares registered with Use
+// run in the order they were added, outermost first.
+type MiddlewareFunc func(HandlerFunc) HandlerFunc
+
+type route struct {
+	endpoint interface{}
+	handler  HandlerFunc
+}
+
+// Use appends middleware that will wrap every handler registered with
+// Handle, regardless of whether it was registered before or after the
+// call to Use.
+func (b *Bot) Use(mw ...MiddlewareFunc) {
+	b.middleware = append(b.middleware, mw...)
+}
+
+// Handle registers a handler for endpoint, which may be:
+//   - a command string such as "/start";
+//   - a *regexp.Regexp matched against the message text;
+//   - one of the On* sentinel constants, matched against the kind of
+//     update (OnText, OnPhoto, OnDocument) or its source (OnQuery,
+//     OnCallback).
+//
+// Routes are tried in registration order and the first match wins.
+// Handlers only run once at least one has been registered; until then,
+// Start and Listen behave exactly as before, delivering solely to the
+// Messages/Queries/Callbacks channels.
+func (b *Bot) Handle(endpoint interface{}, handler func(ctx Context) error) {
+	b.routes = append(b.routes, route{endpoint: endpoint, handler: HandlerFunc(handler)})
+}
+
+// route dispatches a single update to the first matching handler, if
+// any was registered with Handle. Middleware is applied here, at
+// dispatch time, rather than when Handle was called, so a Use() call
+// takes effect for every handler regardless of the order the two were
+// registered in.
+func (b *Bot) route(update Update) {
+	ctx := &nativeContext{
+		bot:      b,
+		message:  update.Payload,
+		query:    update.Query,
+		callback: update.Callback,
+	}
+
+	for _, r := range b.routes {
+		if matches(r.endpoint, update) {
+			h := r.handler
+			for i := len(b.middleware) - 1; i >= 0; i-- {
+				h = b.middleware[i](h)
+			}
+
+			b.dispatch(ctx, h)
+			return
+		}
+	}
+}
+
+// dispatch invokes h, recovering from and logging a panic so a single
+// misbehaving handler can't take down the goroutine poll runs on, and
+// logging an error the handler returns instead of discarding it.
+func (b *Bot) dispatch(ctx Context, h HandlerFunc) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("telebot: handler panicked:", r)
+		}
+	}()
+
+	if err := h(ctx); err != nil {
+		log.Println("telebot: handler returned an error:", err)
+	}
+}
+
+func matches(endpoint interface{}, update Update) bool {
+	switch e := endpoint.(type) {
+	case string:
+		switch e {
+		case OnText:
+			return update.Payload != nil && update.Payload.Text != ""
+		case OnPhoto:
+			return update.Payload != nil && len(update.Payload.Photo) > 0
+		case OnDocument:
+			return update.Payload != nil && update.Payload.Document.Exists()
+		case OnQuery:
+			return update.Query != nil
+		case OnCallback:
+			return update.Callback != nil
+		default:
+			if update.Payload == nil {
+				return false
+			}
+			command := strings.Fields(update.Payload.Text)
+			return len(command) > 0 && command[0] == e
+		}
+	case *regexp.Regexp:
+		return update.Payload != nil && e.MatchString(update.Payload.Text)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package telebot
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint interface{}
+		update   Update
+		want     bool
+	}{
+		{
+			name:     "command match",
+			endpoint: "/start",
+			update:   Update{Payload: &Message{Text: "/start"}},
+			want:     true,
+		},
+		{
+			name:     "command mismatch",
+			endpoint: "/start",
+			update:   Update{Payload: &Message{Text: "/stop"}},
+			want:     false,
+		},
+		{
+			name:     "command match ignores trailing args",
+			endpoint: "/start",
+			update:   Update{Payload: &Message{Text: "/start now"}},
+			want:     true,
+		},
+		{
+			name:     "command endpoint with nil payload",
+			endpoint: "/start",
+			update:   Update{},
+			want:     false,
+		},
+		{
+			name:     "OnText matches non-empty text",
+			endpoint: OnText,
+			update:   Update{Payload: &Message{Text: "hello"}},
+			want:     true,
+		},
+		{
+			name:     "OnText rejects empty text",
+			endpoint: OnText,
+			update:   Update{Payload: &Message{Text: ""}},
+			want:     false,
+		},
+		{
+			name:     "OnQuery matches a query update",
+			endpoint: OnQuery,
+			update:   Update{Query: &Query{}},
+			want:     true,
+		},
+		{
+			name:     "OnQuery rejects a message update",
+			endpoint: OnQuery,
+			update:   Update{Payload: &Message{Text: "hi"}},
+			want:     false,
+		},
+		{
+			name:     "OnCallback matches a callback update",
+			endpoint: OnCallback,
+			update:   Update{Callback: &Callback{}},
+			want:     true,
+		},
+		{
+			name:     "regexp endpoint match",
+			endpoint: regexp.MustCompile(`^/order \d+$`),
+			update:   Update{Payload: &Message{Text: "/order 42"}},
+			want:     true,
+		},
+		{
+			name:     "regexp endpoint mismatch",
+			endpoint: regexp.MustCompile(`^/order \d+$`),
+			update:   Update{Payload: &Message{Text: "/order abc"}},
+			want:     false,
+		},
+		{
+			name:     "unsupported endpoint type",
+			endpoint: 42,
+			update:   Update{Payload: &Message{Text: "hi"}},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matches(tt.endpoint, tt.update); got != tt.want {
+				t.Errorf("matches(%v, %+v) = %v, want %v", tt.endpoint, tt.update, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookInfo reflects the result of a getWebhookInfo call, describing
+// the webhook currently registered for the bot, if any.
+type WebhookInfo struct {
+	URL                  string   `json:"url"`
+	HasCustomCertificate bool     `json:"has_custom_certificate"`
+	PendingUpdateCount   int      `json:"pending_update_count"`
+	LastErrorDate        int64    `json:"last_error_date"`
+	LastErrorMessage     string   `json:"last_error_message"`
+	MaxConnections       int      `json:"max_connections"`
+	AllowedUpdates       []string `json:"allowed_updates"`
+}
+
+type webhookInfoResponse struct {
+	Ok          bool
+	Result      WebhookInfo
+	Description string
+}
+
+// ListenForWebhook returns an http.Handler that decodes incoming updates
+// posted by Telegram to `pattern` and dispatches them into the same
+// Messages/Queries/Callbacks channels that poll() feeds, so the same
+// consuming code works whether the bot is started with Start or deployed
+// behind a webhook.
+//
+// If Bot.SecretToken is set, requests whose
+// X-Telegram-Bot-Api-Secret-Token header doesn't match are rejected with
+// 401 Unauthorized, as recommended by Telegram to keep the endpoint from
+// being spoofed.
+func (b *Bot) ListenForWebhook(pattern string) (http.Handler, error) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if b.SecretToken != "" && r.Header.Get("X-Telegram-Bot-Api-Secret-Token") != b.SecretToken {
+			http.Error(w, "invalid secret token", http.StatusUnauthorized)
+			return
+		}
+
+		var update Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		b.dispatchUpdate(update)
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux, nil
+}
+
+// SetWebhook registers `url` with Telegram as the bot's webhook, so
+// updates are pushed to it instead of being polled with getUpdates.
+// If cert is given and doesn't already exist on Telegram's servers, its
+// file is uploaded as the webhook's self-signed certificate.
+//
+// allowedUpdates restricts which update types are delivered, mirroring
+// the `allowed_updates` parameter of getUpdates; pass nil to receive all
+// of them. Bot.SecretToken and Bot.MaxConnections, if set, are sent
+// along as the `secret_token` and `max_connections` parameters.
+func (b *Bot) SetWebhook(url string, cert *Photo, allowedUpdates []string) error {
+	params := map[string]string{
+		"url": url,
+	}
+
+	if b.SecretToken != "" {
+		params["secret_token"] = b.SecretToken
+	}
+
+	if b.MaxConnections != 0 {
+		params["max_connections"] = fmt.Sprintf("%d", b.MaxConnections)
+	}
+
+	if len(allowedUpdates) > 0 {
+		allowedJSON, err := json.Marshal(allowedUpdates)
+		if err != nil {
+			return err
+		}
+		params["allowed_updates"] = string(allowedJSON)
+	}
+
+	var responseJSON []byte
+	var err error
+
+	if cert != nil && !cert.Exists() {
+		responseJSON, err = b.callFile("setWebhook", "certificate", cert.filename, params)
+	} else {
+		if cert != nil {
+			params["certificate"] = cert.FileID
+		}
+		responseJSON, err = b.call("setWebhook", params)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var responseRecieved messageResponse
+	return json.Unmarshal(responseJSON, &responseRecieved)
+}
+
+// DeleteWebhook removes the bot's webhook integration, if any, reverting
+// delivery back to getUpdates polling.
+func (b *Bot) DeleteWebhook() error {
+	_, err := b.call("deleteWebhook", map[string]string{})
+	return err
+}
+
+// GetWebhookInfo reports the webhook currently registered for the bot,
+// as returned by Telegram's getWebhookInfo.
+func (b *Bot) GetWebhookInfo() (WebhookInfo, error) {
+	var responseRecieved webhookInfoResponse
+
+	responseJSON, err := b.call("getWebhookInfo", map[string]string{})
+	if err != nil {
+		return responseRecieved.Result, err
+	}
+
+	if err := json.Unmarshal(responseJSON, &responseRecieved); err != nil {
+		return responseRecieved.Result, err
+	}
+
+	return responseRecieved.Result, nil
+}
+
+// dispatchUpdate delivers a single update to the Messages/Queries/Callbacks
+// channels, the same way poll does for polled updates.
+func (b *Bot) dispatchUpdate(update Update) {
+	if update.Payload != nil {
+		if b.Messages != nil {
+			b.Messages <- *update.Payload
+		}
+	} else if update.Query != nil {
+		if b.Queries != nil {
+			b.Queries <- *update.Query
+		}
+	} else if update.Callback != nil {
+		if b.Callbacks != nil {
+			b.Callbacks <- *update.Callback
+		}
+	}
+
+	if len(b.routes) > 0 {
+		b.route(update)
+	}
+}